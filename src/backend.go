@@ -0,0 +1,57 @@
+package python
+
+import (
+	"github.com/parithera/plugin-python/src/runner"
+)
+
+// defaultVenvCacheDir is where VenvRunner caches virtualenvs when the
+// analysis config doesn't override it.
+const defaultVenvCacheDir = "/tmp/plugin-python-venvs"
+
+// selectRunner picks the execution backend for a script from the analysis's
+// `python.backend` config ("host", "venv", "chroot" or "docker"), defaulting
+// to HostRunner - the plugin's original, unsandboxed behaviour - so existing
+// deployments are unaffected until they opt in.
+func selectRunner(pythonConfig map[string]interface{}, sourceCodeDir string) runner.Runner {
+	backend, _ := pythonConfig["backend"].(string)
+
+	switch backend {
+	case "venv":
+		baseDir := defaultVenvCacheDir
+		if configured, ok := pythonConfig["venv_cache_dir"].(string); ok && configured != "" {
+			baseDir = configured
+		}
+		return runner.NewVenvRunner(baseDir)
+	case "chroot":
+		chrootDir, _ := pythonConfig["chroot_dir"].(string)
+		return runner.NewChrootRunner(chrootDir, sourceCodeDir)
+	case "docker":
+		image, _ := pythonConfig["docker_image"].(string)
+		dockerRunner := runner.NewDockerRunner(image, sourceCodeDir)
+		if cpus, ok := pythonConfig["docker_cpus"].(string); ok {
+			dockerRunner.CPUs = cpus
+		}
+		return dockerRunner
+	default:
+		return runner.NewHostRunner()
+	}
+}
+
+// resolveLimits reads the resource caps a script must respect from the
+// `python` analysis config, applying conservative defaults when unset.
+func resolveLimits(pythonConfig map[string]interface{}) runner.Limits {
+	limits := runner.Limits{
+		MemoryBytes: 1 << 30, // 1 GiB
+		Processes:   64,
+	}
+	if configured, ok := pythonConfig["memory_limit_bytes"].(float64); ok && configured > 0 {
+		limits.MemoryBytes = int64(configured)
+	}
+	if configured, ok := pythonConfig["cpu_seconds"].(float64); ok && configured > 0 {
+		limits.CPUSeconds = int(configured)
+	}
+	if configured, ok := pythonConfig["max_processes"].(float64); ok && configured > 0 {
+		limits.Processes = int(configured)
+	}
+	return limits
+}
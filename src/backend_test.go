@@ -0,0 +1,67 @@
+package python
+
+import (
+	"testing"
+
+	"github.com/parithera/plugin-python/src/runner"
+)
+
+func TestResolveLimitsDefaults(t *testing.T) {
+	limits := resolveLimits(map[string]interface{}{})
+
+	if limits.MemoryBytes != 1<<30 {
+		t.Errorf("MemoryBytes = %d, want default of 1 GiB", limits.MemoryBytes)
+	}
+	if limits.Processes != 64 {
+		t.Errorf("Processes = %d, want default of 64", limits.Processes)
+	}
+	if limits.CPUSeconds != 0 {
+		t.Errorf("CPUSeconds = %d, want default of 0 (unlimited)", limits.CPUSeconds)
+	}
+}
+
+func TestResolveLimitsOverrides(t *testing.T) {
+	limits := resolveLimits(map[string]interface{}{
+		"memory_limit_bytes": float64(512 << 20),
+		"cpu_seconds":        float64(30),
+		"max_processes":      float64(8),
+	})
+
+	if limits.MemoryBytes != 512<<20 {
+		t.Errorf("MemoryBytes = %d, want %d", limits.MemoryBytes, 512<<20)
+	}
+	if limits.CPUSeconds != 30 {
+		t.Errorf("CPUSeconds = %d, want 30", limits.CPUSeconds)
+	}
+	if limits.Processes != 8 {
+		t.Errorf("Processes = %d, want 8", limits.Processes)
+	}
+}
+
+func TestResolveLimitsIgnoresNonPositiveOverrides(t *testing.T) {
+	limits := resolveLimits(map[string]interface{}{
+		"memory_limit_bytes": float64(0),
+		"max_processes":      float64(-1),
+	})
+
+	if limits.MemoryBytes != 1<<30 {
+		t.Errorf("MemoryBytes = %d, want the default to survive a non-positive override", limits.MemoryBytes)
+	}
+	if limits.Processes != 64 {
+		t.Errorf("Processes = %d, want the default to survive a non-positive override", limits.Processes)
+	}
+}
+
+func TestSelectRunnerDefaultsToHost(t *testing.T) {
+	backend := selectRunner(map[string]interface{}{}, "/tmp/source")
+	if _, ok := backend.(*runner.HostRunner); !ok {
+		t.Errorf("selectRunner() = %T, want *runner.HostRunner when no backend is configured", backend)
+	}
+}
+
+func TestSelectRunnerVenv(t *testing.T) {
+	backend := selectRunner(map[string]interface{}{"backend": "venv"}, "/tmp/source")
+	if _, ok := backend.(*runner.VenvRunner); !ok {
+		t.Errorf("selectRunner() = %T, want *runner.VenvRunner for backend=venv", backend)
+	}
+}
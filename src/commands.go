@@ -0,0 +1,167 @@
+package python
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	exceptionManager "github.com/CodeClarityCE/utility-types/exceptions"
+
+	"github.com/parithera/plugin-python/src/types"
+)
+
+// workflowCommand is a single parsed `::<command> key=value,...::<payload>`
+// line, modelled after GitHub Actions' workflow commands. Scripts emit these
+// on stdout to talk back to the plugin without us having to scrape files.
+type workflowCommand struct {
+	name    string
+	params  map[string]string
+	payload string
+}
+
+// parseWorkflowCommand parses a line of the form
+// `::<command> key=value,key=value::<payload>`. It returns ok=false for any
+// line that isn't a command, so callers can fall back to treating it as
+// plain log output.
+func parseWorkflowCommand(line string) (workflowCommand, bool) {
+	if !strings.HasPrefix(line, "::") {
+		return workflowCommand{}, false
+	}
+	rest := line[len("::"):]
+	end := strings.Index(rest, "::")
+	if end == -1 {
+		return workflowCommand{}, false
+	}
+	header, payload := rest[:end], rest[end+len("::"):]
+
+	name := header
+	params := map[string]string{}
+	if space := strings.IndexByte(header, ' '); space != -1 {
+		name = header[:space]
+		for _, pair := range strings.Split(header[space+1:], ",") {
+			if key, value, ok := strings.Cut(pair, "="); ok {
+				params[key] = value
+			}
+		}
+	}
+	if name == "" {
+		return workflowCommand{}, false
+	}
+	return workflowCommand{name: name, params: params, payload: payload}, true
+}
+
+// commandProcessor accumulates the state of a single script run as its
+// stdout is parsed line by line: masked log text, merged set-output values,
+// emitted images/files, progress, and any notice/warning/error commands.
+type commandProcessor struct {
+	mu sync.Mutex
+
+	log        strings.Builder
+	groupDepth int
+	masks      []string
+
+	outputs     map[string]any
+	images      []types.Image
+	files       []types.File
+	progressPct int
+	errors      []exceptionManager.Error
+}
+
+func newCommandProcessor() *commandProcessor {
+	return &commandProcessor{outputs: map[string]any{}}
+}
+
+// process handles one line of script output, dispatching it to the matching
+// workflow command or, if it isn't one, appending it to the log as-is.
+func (p *commandProcessor) process(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cmd, ok := parseWorkflowCommand(line)
+	if !ok {
+		p.appendLog(line)
+		return
+	}
+
+	switch cmd.name {
+	case "notice":
+		p.addError("notice", cmd.payload)
+	case "warning":
+		p.addError("warning", cmd.payload)
+	case "error":
+		p.addError("error", cmd.payload)
+	case "set-output":
+		if name := cmd.params["name"]; name != "" {
+			p.outputs[name] = p.mask(cmd.payload)
+		}
+	case "add-image":
+		p.images = append(p.images, types.Image{Path: cmd.params["path"], Title: cmd.params["title"]})
+	case "add-file":
+		p.files = append(p.files, types.File{Path: cmd.params["path"]})
+	case "progress":
+		if pct, err := strconv.Atoi(cmd.params["pct"]); err == nil {
+			p.progressPct = pct
+		}
+	case "group":
+		p.groupDepth++
+		p.appendLog(line)
+	case "endgroup":
+		if p.groupDepth > 0 {
+			p.groupDepth--
+		}
+		p.appendLog(line)
+	case "add-mask":
+		if cmd.payload != "" {
+			p.masks = append(p.masks, cmd.payload)
+		}
+	default:
+		p.addError("warning", fmt.Sprintf("unrecognised workflow command %q, kept verbatim", cmd.name))
+		p.appendLog(line)
+	}
+}
+
+// appendLog masks secrets in line before adding it to the accumulated log text.
+func (p *commandProcessor) appendLog(line string) {
+	p.log.WriteString(p.mask(line))
+	p.log.WriteString("\n")
+}
+
+// mask replaces every value registered via `add-mask` with "***" so secrets
+// never reach the persisted log text.
+func (p *commandProcessor) mask(s string) string {
+	for _, secret := range p.masks {
+		if secret == "" {
+			continue
+		}
+		s = strings.ReplaceAll(s, secret, "***")
+	}
+	return s
+}
+
+func (p *commandProcessor) addError(severity string, description string) {
+	p.errors = append(p.errors, exceptionManager.Error{
+		Private: exceptionManager.ErrorContent{
+			Description: p.mask(description),
+			Type:        exceptionManager.GENERIC_ERROR,
+		},
+		Public: exceptionManager.ErrorContent{
+			Description: fmt.Sprintf("[%s] %s", severity, p.mask(description)),
+			Type:        exceptionManager.GENERIC_ERROR,
+		},
+	})
+}
+
+// snapshot returns a consistent view of the processor's current state for
+// incremental status updates and for building the final Result.
+func (p *commandProcessor) snapshot() (text string, progressPct int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.log.String(), p.progressPct
+}
+
+func (p *commandProcessor) result() (outputs map[string]any, images []types.Image, files []types.File, errs []exceptionManager.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.outputs, p.images, p.files, p.errors
+}
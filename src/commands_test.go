@@ -0,0 +1,167 @@
+package python
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWorkflowCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		wantOK      bool
+		wantName    string
+		wantParams  map[string]string
+		wantPayload string
+	}{
+		{
+			name:        "simple command with no params",
+			line:        "::endgroup::",
+			wantOK:      true,
+			wantName:    "endgroup",
+			wantParams:  map[string]string{},
+			wantPayload: "",
+		},
+		{
+			name:        "command with single param and payload",
+			line:        "::progress pct=42::halfway there",
+			wantOK:      true,
+			wantName:    "progress",
+			wantParams:  map[string]string{"pct": "42"},
+			wantPayload: "halfway there",
+		},
+		{
+			name:        "command with multiple params",
+			line:        "::add-image path=plot.png,title=My Plot::",
+			wantOK:      true,
+			wantName:    "add-image",
+			wantParams:  map[string]string{"path": "plot.png", "title": "My Plot"},
+			wantPayload: "",
+		},
+		{
+			name:   "plain log line is not a command",
+			line:   "this is just regular stdout",
+			wantOK: false,
+		},
+		{
+			name:   "missing closing delimiter is not a command",
+			line:   "::progress pct=42",
+			wantOK: false,
+		},
+		{
+			name:   "empty command name is not a command",
+			line:   ":: ::payload",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := parseWorkflowCommand(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parseWorkflowCommand(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if cmd.name != tt.wantName {
+				t.Errorf("name = %q, want %q", cmd.name, tt.wantName)
+			}
+			if cmd.payload != tt.wantPayload {
+				t.Errorf("payload = %q, want %q", cmd.payload, tt.wantPayload)
+			}
+			if len(cmd.params) != len(tt.wantParams) {
+				t.Fatalf("params = %v, want %v", cmd.params, tt.wantParams)
+			}
+			for k, v := range tt.wantParams {
+				if cmd.params[k] != v {
+					t.Errorf("params[%q] = %q, want %q", k, cmd.params[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestCommandProcessorSetOutputAndMask(t *testing.T) {
+	p := newCommandProcessor()
+
+	p.process("::add-mask::s3cr3t")
+	p.process("::set-output name=token::s3cr3t")
+	p.process("a log line mentioning s3cr3t")
+
+	outputs, _, _, _ := p.result()
+	if outputs["token"] != "***" {
+		t.Errorf("outputs[token] = %v, want masked value", outputs["token"])
+	}
+
+	text, _ := p.snapshot()
+	if strings.Contains(text, "s3cr3t") {
+		t.Errorf("snapshot text %q still contains the masked secret", text)
+	}
+}
+
+func TestCommandProcessorImagesAndFiles(t *testing.T) {
+	p := newCommandProcessor()
+
+	p.process("::add-image path=plot.png,title=Plot::")
+	p.process("::add-file path=report.txt::")
+
+	_, images, files, _ := p.result()
+	if len(images) != 1 || images[0].Path != "plot.png" || images[0].Title != "Plot" {
+		t.Errorf("images = %+v, want one image plot.png/Plot", images)
+	}
+	if len(files) != 1 || files[0].Path != "report.txt" {
+		t.Errorf("files = %+v, want one file report.txt", files)
+	}
+}
+
+func TestCommandProcessorProgress(t *testing.T) {
+	p := newCommandProcessor()
+
+	_, pct := p.snapshot()
+	if pct != 0 {
+		t.Fatalf("initial progress = %d, want 0", pct)
+	}
+
+	p.process("::progress pct=42::")
+	_, pct = p.snapshot()
+	if pct != 42 {
+		t.Errorf("progress = %d, want 42", pct)
+	}
+
+	// A malformed percentage is ignored rather than resetting progress.
+	p.process("::progress pct=not-a-number::")
+	_, pct = p.snapshot()
+	if pct != 42 {
+		t.Errorf("progress after malformed update = %d, want unchanged 42", pct)
+	}
+}
+
+func TestCommandProcessorNoticeWarningError(t *testing.T) {
+	p := newCommandProcessor()
+
+	p.process("::notice::heads up")
+	p.process("::warning::careful")
+	p.process("::error::broken")
+
+	_, _, _, errs := p.result()
+	if len(errs) != 3 {
+		t.Fatalf("got %d errors, want 3", len(errs))
+	}
+}
+
+func TestCommandProcessorUnknownCommandKeptVerbatim(t *testing.T) {
+	p := newCommandProcessor()
+
+	p.process("::mystery key=value::payload")
+
+	text, _ := p.snapshot()
+	if !strings.Contains(text, "::mystery key=value::payload") {
+		t.Errorf("snapshot text %q does not preserve the unknown command verbatim", text)
+	}
+
+	_, _, _, errs := p.result()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors for unknown command, want 1 warning", len(errs))
+	}
+}
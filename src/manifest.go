@@ -0,0 +1,213 @@
+package python
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/parithera/plugin-python/src/types"
+)
+
+// manifestFileName is the file a script writes to outputPath to describe
+// every artifact it produced, replacing the old convention of assuming
+// exactly one PNG, one TXT and one JSON file.
+const manifestFileName = "manifest.json"
+
+// manifestEntry is one artifact declared in manifest.json.
+type manifestEntry struct {
+	Path        string `json:"path"`
+	Kind        string `json:"kind"`
+	MIME        string `json:"mime"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Role        string `json:"role"`
+}
+
+type scriptManifest struct {
+	Artifacts []manifestEntry `json:"artifacts"`
+}
+
+// loadManifest reads manifest.json from outputPath. A missing manifest is
+// not an error: it means the script follows the legacy convention and the
+// caller should fall back to synthesizeManifest.
+func loadManifest(outputPath string) (*scriptManifest, error) {
+	raw, err := os.ReadFile(filepath.Join(outputPath, manifestFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest scriptManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// synthesizeManifest is the compatibility shim for scripts that don't write
+// a manifest.json yet: it reconstructs one from the old single-PNG/TXT/JSON
+// convention so they keep working unmodified. declared is the set of paths
+// already claimed by `::add-image::`/`::add-file::` workflow commands (see
+// commands.go); those are skipped here and merged in separately by
+// mergeCommandArtifacts so a script using both conventions for the same file
+// doesn't end up with two manifest entries for it.
+func synthesizeManifest(outputPath string, declared map[string]bool) (*scriptManifest, error) {
+	files, err := filepath.Glob(filepath.Join(outputPath, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &scriptManifest{}
+	for _, f := range files {
+		base := filepath.Base(f)
+		if base == manifestFileName || base == "groups.json" || declared[base] {
+			continue
+		}
+
+		kind, mime := artifactKindAndMIME(base)
+		if kind == "" {
+			continue
+		}
+		manifest.Artifacts = append(manifest.Artifacts, manifestEntry{Path: base, Kind: kind, MIME: mime})
+	}
+	return manifest, nil
+}
+
+// artifactKindAndMIME infers an artifact's kind and MIME type from its file
+// extension, for files synthesizeManifest discovers without an explicit
+// declaration. An empty kind means the extension isn't one we recognise.
+func artifactKindAndMIME(path string) (kind string, mime string) {
+	switch filepath.Ext(path) {
+	case ".png":
+		return "image", "image/png"
+	case ".txt":
+		return "text", "text/plain"
+	case ".json":
+		return "json", "application/json"
+	default:
+		return "", ""
+	}
+}
+
+// declaredArtifactPaths returns the set of artifact paths already registered
+// via `::add-image::`/`::add-file::` workflow commands, so synthesizeManifest
+// can skip them instead of picking them up a second time from outputPath.
+func declaredArtifactPaths(images []types.Image, files []types.File) map[string]bool {
+	declared := make(map[string]bool, len(images)+len(files))
+	for _, image := range images {
+		declared[image.Path] = true
+	}
+	for _, file := range files {
+		declared[file.Path] = true
+	}
+	return declared
+}
+
+// mergeCommandArtifacts folds images and files declared via workflow
+// commands into manifest, so they go through the same
+// materialize-and-content-address step as manifest.json-declared artifacts
+// instead of being reported as raw, unmoved paths that dangle once
+// materializeArtifacts deletes the originals out of outputPath.
+func mergeCommandArtifacts(manifest *scriptManifest, images []types.Image, files []types.File) *scriptManifest {
+	if manifest == nil {
+		manifest = &scriptManifest{}
+	}
+
+	seen := make(map[string]bool, len(manifest.Artifacts))
+	for _, entry := range manifest.Artifacts {
+		seen[entry.Path] = true
+	}
+
+	for _, image := range images {
+		if seen[image.Path] {
+			continue
+		}
+		seen[image.Path] = true
+		kind, mime := artifactKindAndMIME(image.Path)
+		if kind == "" {
+			kind = "image"
+		}
+		manifest.Artifacts = append(manifest.Artifacts, manifestEntry{Path: image.Path, Kind: kind, MIME: mime, Title: image.Title})
+	}
+	for _, file := range files {
+		if seen[file.Path] {
+			continue
+		}
+		seen[file.Path] = true
+		manifest.Artifacts = append(manifest.Artifacts, manifestEntry{Path: file.Path, Kind: "file"})
+	}
+
+	return manifest
+}
+
+// resolveArtifactPath resolves a manifest entry's path against outputPath,
+// rejecting anything that isn't a plain path inside it. manifest.json is
+// written by the script itself and read on the host, outside any
+// chroot/container sandbox, so an absolute path or a "../" escape must never
+// be allowed to make materializeArtifacts read (and then delete) an
+// arbitrary file the host process can reach.
+func resolveArtifactPath(outputPath string, entryPath string) (string, error) {
+	if entryPath == "" {
+		return "", fmt.Errorf("artifact path must not be empty")
+	}
+	if filepath.IsAbs(entryPath) {
+		return "", fmt.Errorf("artifact path %q must be relative to the script's output directory", entryPath)
+	}
+
+	base := filepath.Clean(outputPath)
+	full := filepath.Clean(filepath.Join(base, entryPath))
+	if full != base && !strings.HasPrefix(full, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("artifact path %q escapes the script's output directory", entryPath)
+	}
+	return full, nil
+}
+
+// materializeArtifacts moves every artifact a manifest declares out of
+// outputPath and into dataPath under a content-addressed name (a sha256
+// prefix of its bytes plus its original extension), so identical artifacts
+// - whether from a rerun of the same analysis or a different one - collapse
+// onto the same file instead of being duplicated on disk.
+func materializeArtifacts(manifest *scriptManifest, outputPath string, dataPath string) ([]types.Artifact, error) {
+	artifacts := make([]types.Artifact, 0, len(manifest.Artifacts))
+
+	for _, entry := range manifest.Artifacts {
+		srcPath, err := resolveArtifactPath(outputPath, entry.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(srcPath)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(data)
+		id := hex.EncodeToString(sum[:])[:16]
+		destPath := filepath.Join(dataPath, id+filepath.Ext(entry.Path))
+
+		if _, err := os.Stat(destPath); os.IsNotExist(err) {
+			if err := os.WriteFile(destPath, data, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		os.Remove(srcPath)
+
+		artifacts = append(artifacts, types.Artifact{
+			ID:          id,
+			Path:        destPath,
+			Kind:        entry.Kind,
+			MIME:        entry.MIME,
+			Title:       entry.Title,
+			Description: entry.Description,
+			Role:        entry.Role,
+		})
+	}
+
+	return artifacts, nil
+}
@@ -0,0 +1,225 @@
+package python
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/parithera/plugin-python/src/types"
+)
+
+func TestLoadManifestMissing(t *testing.T) {
+	manifest, err := loadManifest(t.TempDir())
+	if err != nil {
+		t.Fatalf("loadManifest() error = %s, want nil", err)
+	}
+	if manifest != nil {
+		t.Errorf("loadManifest() = %+v, want nil for a missing manifest.json", manifest)
+	}
+}
+
+func TestLoadManifestPresent(t *testing.T) {
+	outputPath := t.TempDir()
+	raw, _ := json.Marshal(scriptManifest{Artifacts: []manifestEntry{{Path: "plot.png", Kind: "image"}}})
+	if err := os.WriteFile(filepath.Join(outputPath, manifestFileName), raw, 0o644); err != nil {
+		t.Fatalf("failed to write manifest.json: %s", err)
+	}
+
+	manifest, err := loadManifest(outputPath)
+	if err != nil {
+		t.Fatalf("loadManifest() error = %s", err)
+	}
+	if len(manifest.Artifacts) != 1 || manifest.Artifacts[0].Path != "plot.png" {
+		t.Errorf("loadManifest() = %+v, want one artifact plot.png", manifest)
+	}
+}
+
+func TestSynthesizeManifest(t *testing.T) {
+	outputPath := t.TempDir()
+	for _, name := range []string{"plot.png", "report.txt", "data.json", manifestFileName, "groups.json"} {
+		if err := os.WriteFile(filepath.Join(outputPath, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %s", name, err)
+		}
+	}
+
+	manifest, err := synthesizeManifest(outputPath, map[string]bool{})
+	if err != nil {
+		t.Fatalf("synthesizeManifest() error = %s", err)
+	}
+
+	got := map[string]manifestEntry{}
+	for _, entry := range manifest.Artifacts {
+		got[entry.Path] = entry
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("synthesizeManifest() produced %d artifacts, want 3 (manifest.json/groups.json excluded): %+v", len(got), manifest.Artifacts)
+	}
+	if got["plot.png"].Kind != "image" || got["plot.png"].MIME != "image/png" {
+		t.Errorf("plot.png entry = %+v, want kind=image mime=image/png", got["plot.png"])
+	}
+	if got["report.txt"].Kind != "text" || got["report.txt"].MIME != "text/plain" {
+		t.Errorf("report.txt entry = %+v, want kind=text mime=text/plain", got["report.txt"])
+	}
+	if got["data.json"].Kind != "json" || got["data.json"].MIME != "application/json" {
+		t.Errorf("data.json entry = %+v, want kind=json mime=application/json", got["data.json"])
+	}
+}
+
+func TestSynthesizeManifestSkipsDeclaredPaths(t *testing.T) {
+	outputPath := t.TempDir()
+	for _, name := range []string{"plot.png", "report.txt"} {
+		if err := os.WriteFile(filepath.Join(outputPath, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %s", name, err)
+		}
+	}
+
+	manifest, err := synthesizeManifest(outputPath, map[string]bool{"plot.png": true})
+	if err != nil {
+		t.Fatalf("synthesizeManifest() error = %s", err)
+	}
+
+	for _, entry := range manifest.Artifacts {
+		if entry.Path == "plot.png" {
+			t.Errorf("synthesizeManifest() re-included declared path %q", entry.Path)
+		}
+	}
+	if len(manifest.Artifacts) != 1 || manifest.Artifacts[0].Path != "report.txt" {
+		t.Errorf("synthesizeManifest() = %+v, want only report.txt", manifest.Artifacts)
+	}
+}
+
+func TestMergeCommandArtifacts(t *testing.T) {
+	manifest := &scriptManifest{Artifacts: []manifestEntry{{Path: "data.json", Kind: "json"}}}
+	images := []types.Image{{Path: "plot.png", Title: "Plot"}}
+	files := []types.File{{Path: "report.txt"}}
+
+	merged := mergeCommandArtifacts(manifest, images, files)
+
+	got := map[string]manifestEntry{}
+	for _, entry := range merged.Artifacts {
+		got[entry.Path] = entry
+	}
+	if len(got) != 3 {
+		t.Fatalf("merged manifest has %d artifacts, want 3: %+v", len(got), merged.Artifacts)
+	}
+	if got["plot.png"].Kind != "image" || got["plot.png"].Title != "Plot" {
+		t.Errorf("plot.png entry = %+v, want kind=image title=Plot", got["plot.png"])
+	}
+	if got["report.txt"].Kind != "file" {
+		t.Errorf("report.txt entry = %+v, want kind=file", got["report.txt"])
+	}
+}
+
+func TestMergeCommandArtifactsSkipsAlreadyDeclared(t *testing.T) {
+	manifest := &scriptManifest{Artifacts: []manifestEntry{{Path: "plot.png", Kind: "image", Title: "From manifest"}}}
+	images := []types.Image{{Path: "plot.png", Title: "From add-image"}}
+
+	merged := mergeCommandArtifacts(manifest, images, nil)
+
+	if len(merged.Artifacts) != 1 {
+		t.Fatalf("got %d artifacts, want 1 (no duplicate for plot.png): %+v", len(merged.Artifacts), merged.Artifacts)
+	}
+	if merged.Artifacts[0].Title != "From manifest" {
+		t.Errorf("merge should keep the manifest's own entry, got %+v", merged.Artifacts[0])
+	}
+}
+
+func TestResolveArtifactPathRejectsAbsoluteAndEscaping(t *testing.T) {
+	outputPath := "/tmp/analysis-output"
+
+	tests := []string{"/etc/passwd", "../secrets.txt", "a/../../escape.txt"}
+	for _, entryPath := range tests {
+		if _, err := resolveArtifactPath(outputPath, entryPath); err == nil {
+			t.Errorf("resolveArtifactPath(%q, %q) = nil error, want a rejection", outputPath, entryPath)
+		}
+	}
+
+	got, err := resolveArtifactPath(outputPath, "plot.png")
+	if err != nil {
+		t.Fatalf("resolveArtifactPath() error = %s, want nil for a plain relative path", err)
+	}
+	if want := filepath.Join(outputPath, "plot.png"); got != want {
+		t.Errorf("resolveArtifactPath() = %q, want %q", got, want)
+	}
+}
+
+func TestMaterializeArtifactsRejectsEscapingPath(t *testing.T) {
+	outputPath := t.TempDir()
+	dataPath := t.TempDir()
+
+	manifest := &scriptManifest{Artifacts: []manifestEntry{{Path: "../../etc/passwd", Kind: "text"}}}
+
+	if _, err := materializeArtifacts(manifest, outputPath, dataPath); err == nil {
+		t.Errorf("materializeArtifacts() = nil error, want a rejection for a path escaping outputPath")
+	}
+}
+
+func TestMaterializeArtifacts(t *testing.T) {
+	outputPath := t.TempDir()
+	dataPath := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(outputPath, "plot.png"), []byte("pixels"), 0o644); err != nil {
+		t.Fatalf("failed to write plot.png: %s", err)
+	}
+
+	manifest := &scriptManifest{Artifacts: []manifestEntry{
+		{Path: "plot.png", Kind: "image", MIME: "image/png", Title: "Plot"},
+	}}
+
+	artifacts, err := materializeArtifacts(manifest, outputPath, dataPath)
+	if err != nil {
+		t.Fatalf("materializeArtifacts() error = %s", err)
+	}
+	if len(artifacts) != 1 {
+		t.Fatalf("got %d artifacts, want 1", len(artifacts))
+	}
+
+	artifact := artifacts[0]
+	if artifact.ID == "" || artifact.Kind != "image" || artifact.Title != "Plot" {
+		t.Errorf("artifact = %+v, want a non-empty ID, kind=image, title=Plot", artifact)
+	}
+	if _, err := os.Stat(artifact.Path); err != nil {
+		t.Errorf("artifact path %q does not exist: %s", artifact.Path, err)
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, "plot.png")); !os.IsNotExist(err) {
+		t.Errorf("source file still exists at %s after materialization", filepath.Join(outputPath, "plot.png"))
+	}
+}
+
+func TestMaterializeArtifactsDedupesByContent(t *testing.T) {
+	outputPath := t.TempDir()
+	dataPath := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(outputPath, "a.txt"), []byte("same content"), 0o644); err != nil {
+		t.Fatalf("failed to write a.txt: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputPath, "b.txt"), []byte("same content"), 0o644); err != nil {
+		t.Fatalf("failed to write b.txt: %s", err)
+	}
+
+	manifest := &scriptManifest{Artifacts: []manifestEntry{
+		{Path: "a.txt", Kind: "text"},
+		{Path: "b.txt", Kind: "text"},
+	}}
+
+	artifacts, err := materializeArtifacts(manifest, outputPath, dataPath)
+	if err != nil {
+		t.Fatalf("materializeArtifacts() error = %s", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("got %d artifacts, want 2", len(artifacts))
+	}
+	if artifacts[0].ID != artifacts[1].ID || artifacts[0].Path != artifacts[1].Path {
+		t.Errorf("identical content produced different artifacts: %+v vs %+v", artifacts[0], artifacts[1])
+	}
+
+	entries, err := os.ReadDir(dataPath)
+	if err != nil {
+		t.Fatalf("failed to read dataPath: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dataPath contains %d files, want 1 deduplicated file", len(entries))
+	}
+}
@@ -2,15 +2,17 @@ package python
 
 import (
 	"bufio"
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path"
 	"path/filepath"
-	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
@@ -18,10 +20,18 @@ import (
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 
+	"github.com/parithera/plugin-python/src/runner"
 	"github.com/parithera/plugin-python/src/types"
 	"github.com/parithera/plugin-python/src/utils/output_generator"
 )
 
+// defaultTimeoutSeconds bounds how long a script may run when the analysis
+// config does not specify "python.timeout_seconds".
+const defaultTimeoutSeconds = 30 * 60
+
+// killGracePeriod is how long we wait after SIGTERM before escalating to SIGKILL.
+const killGracePeriod = 10 * time.Second
+
 // Start initiates the Python analysis process.
 // It fetches analysis details, executes the Python script, and updates the chat history with the results.
 func Start(sourceCodeDir string, analysisId uuid.UUID, codeclarityDB *bun.DB) types.Output {
@@ -43,6 +53,12 @@ func Start(sourceCodeDir string, analysisId uuid.UUID, codeclarityDB *bun.DB) ty
 	// Extract the project ID from the Python configuration.
 	projectId := python_config["project"].(string)
 
+	// Resolve the script timeout, falling back to the default when unset.
+	timeoutSeconds := defaultTimeoutSeconds
+	if configured, ok := python_config["timeout_seconds"].(float64); ok && configured > 0 {
+		timeoutSeconds = int(configured)
+	}
+
 	// Fetch chat history associated with the project ID.
 	var chat types.Chat
 	err = codeclarityDB.NewSelect().Model(&chat).Where("? = ?", bun.Ident("projectId"), projectId).Scan(context.Background())
@@ -56,13 +72,35 @@ func Start(sourceCodeDir string, analysisId uuid.UUID, codeclarityDB *bun.DB) ty
 		}
 	}
 
+	// Derive a cancellable context bounded by the configured timeout, and
+	// cancel it on SIGINT/SIGTERM so the script can be interrupted cleanly.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		select {
+		case <-sigs:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	// Execute the Python script and obtain the output.
-	out := ExecuteScript(sourceCodeDir, analysisId)
+	runnerBackend := selectRunner(python_config, sourceCodeDir)
+	out := ExecuteScript(ctx, sourceCodeDir, analysisId, codeclarityDB, &chat, python_config, runnerBackend)
 
 	// Update the chat message with the script's results.
 	chat.Messages[0].Text = out.Result.Text
 	chat.Messages[0].Image = out.Result.Image
+	chat.Messages[0].Images = out.Result.Images
+	chat.Messages[0].Files = out.Result.Files
+	chat.Messages[0].Artifacts = out.Result.Artifacts
 	chat.Messages[0].JSON = out.Result.Data
+	chat.Messages[0].RawJSON = out.Result.RawJSON
+	chat.Messages[0].Status = string(out.AnalysisInfo.Status)
 
 	// Execute the update operation in the database.
 	_, err = codeclarityDB.NewUpdate().Model(&chat).WherePK().Exec(context.Background())
@@ -75,7 +113,21 @@ func Start(sourceCodeDir string, analysisId uuid.UUID, codeclarityDB *bun.DB) ty
 }
 
 // ExecuteScript executes the Python script and processes the results.
-func ExecuteScript(sourceCodeDir string, analysisId uuid.UUID) types.Output {
+// The supplied context bounds the script's lifetime: cancelling it (timeout
+// or signal) aborts the child process and persists a partial, failed result.
+//
+// While the script runs, its stdout is parsed line by line as a stream of
+// workflow commands (see commands.go) - ordinary log lines, notices,
+// warnings, errors, set-output/add-image/add-file declarations, progress
+// updates and masked secrets - and chat.Messages[0] is updated incrementally
+// so a caller watching the chat row sees live progress. stderr is captured
+// the same way but only ever contributes log text.
+//
+// If the script writes a JSON file (and isn't named groups.json), it is
+// validated against the schema declared via pythonConfig["schema"] or a
+// schema.json next to script.py, if either is present; a script producing
+// JSON that doesn't conform to its declared schema fails the analysis.
+func ExecuteScript(ctx context.Context, sourceCodeDir string, analysisId uuid.UUID, codeclarityDB *bun.DB, chat *types.Chat, pythonConfig map[string]interface{}, runnerBackend runner.Runner) types.Output {
 	// Record the start time for performance analysis.
 	start := time.Now()
 
@@ -90,7 +142,7 @@ func ExecuteScript(sourceCodeDir string, analysisId uuid.UUID) types.Output {
 
 	// If the script is not found, return a failure output.
 	if len(files) == 0 {
-		return generate_output(start, "", nil, "", codeclarity.FAILURE, []exceptionManager.Error{})
+		return generate_output(start, types.Result{}, codeclarity.FAILURE, []exceptionManager.Error{})
 	}
 
 	// Construct the output and data paths.
@@ -101,109 +153,262 @@ func ExecuteScript(sourceCodeDir string, analysisId uuid.UUID) types.Output {
 	os.MkdirAll(outputPath, os.ModePerm)
 	os.MkdirAll(dataPath, os.ModePerm)
 
-	// Define the arguments for the Python script execution.
-	args := []string{scriptPath, outputPath}
-
-	// Execute the Python script using the 'python3' command.
-	cmd := exec.Command("python3", args...)
-	message, err := cmd.CombinedOutput()
+	// Build the command via the configured backend (host, venv, chroot or
+	// docker - see backend.go), bound to ctx so the caller can cancel or
+	// time it out, and subject to the analysis's resource limits.
+	cmd, cleanupRunner, err := runnerBackend.Command(ctx, scriptPath, outputPath, resolveLimits(pythonConfig))
 	if err != nil {
-		// Create an error object with the error message and type.
 		codeclarity_error := exceptionManager.Error{
 			Private: exceptionManager.ErrorContent{
-				Description: string(message),
+				Description: err.Error(),
 				Type:        exceptionManager.GENERIC_ERROR,
 			},
 			Public: exceptionManager.ErrorContent{
-				Description: "The script failed to execute",
+				Description: "Failed to prepare the script's execution environment",
 				Type:        exceptionManager.GENERIC_ERROR,
 			},
 		}
-		// Return a failure output with the error object.
-		return generate_output(start, "", nil, "", codeclarity.FAILURE, []exceptionManager.Error{codeclarity_error})
+		return generate_output(start, types.Result{}, codeclarity.FAILURE, []exceptionManager.Error{codeclarity_error})
+	}
+	// Release whatever the runner set up for this invocation (e.g. a chroot
+	// bind mount) once the script has run, on every return path below.
+	defer cleanupRunner()
+
+	// On cancellation, ask the child to shut down gracefully before the
+	// runtime escalates to SIGKILL once WaitDelay elapses.
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
 	}
+	cmd.WaitDelay = killGracePeriod
 
-	// Find all files in the output path.
-	files, err = filepath.Glob(outputPath + "/*")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Fatal(err)
+	}
+	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Initialize variables to store the image name, text content, and data.
-	image := ""
-	text := ""
-	var data map[string]interface{}
-
-	// Iterate over the files in the output path.
-	for _, f := range files {
-		// Check if the file is a PNG image.
-		if strings.HasSuffix(f, ".png") {
-			// Rename the image file to include the analysis ID.
-			newName := filepath.Join(dataPath, analysisId.String()+".png")
-			os.Rename(f, newName)
-			image = analysisId.String()
+	processor := newCommandProcessor()
+
+	streamPipe := func(pipe io.ReadCloser, parseCommands bool, wg *sync.WaitGroup) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(pipe)
+		for scanner.Scan() {
+			if parseCommands {
+				processor.process(scanner.Text())
+			} else {
+				processor.mu.Lock()
+				processor.appendLog(scanner.Text())
+				processor.mu.Unlock()
+			}
+			text, progressPct := processor.snapshot()
+			setChatStatus(codeclarityDB, chat, runningStatus(progressPct), text)
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		codeclarity_error := exceptionManager.Error{
+			Private: exceptionManager.ErrorContent{
+				Description: err.Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "The script failed to start",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
 		}
+		return generate_output(start, types.Result{}, codeclarity.FAILURE, []exceptionManager.Error{codeclarity_error})
+	}
 
-		// Check if the file is a TXT file.
-		if strings.HasSuffix(f, ".txt") {
-			// Rename the text file to include the analysis ID.
-			newName := filepath.Join(dataPath, analysisId.String()+".txt")
-			os.Rename(f, newName)
+	setChatStatus(codeclarityDB, chat, runningStatus(0), "")
 
-			// Open the renamed text file.
-			txtFile, err := os.Open(newName)
-			if err != nil {
-				panic(fmt.Sprintf("Failed to open text file: %s", err.Error()))
-			}
-			defer txtFile.Close()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go streamPipe(stdout, true, &wg)
+	go streamPipe(stderr, false, &wg)
+	wg.Wait()
 
-			// Read the content of the text file into a buffer.
-			var buffer bytes.Buffer
-			scanner := bufio.NewScanner(txtFile)
-			for scanner.Scan() {
-				buffer.WriteString(scanner.Text() + "\n")
-			}
-			text = buffer.String()
+	err = cmd.Wait()
+
+	finalLog, _ := processor.snapshot()
+	outputs, cmdImages, cmdFiles, cmdErrors := processor.result()
+
+	if ctx.Err() != nil {
+		// The context was cancelled (timeout or signal) before the script
+		// finished on its own: persist a partial, aborted result. The script
+		// never got to write its manifest, so the best we can report is
+		// whatever it already declared via add-image/add-file.
+		setChatStatus(codeclarityDB, chat, "aborted", finalLog)
+		codeclarity_error := exceptionManager.Error{
+			Private: exceptionManager.ErrorContent{
+				Description: ctx.Err().Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "The script was aborted before it completed",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
 		}
+		result := types.Result{Text: finalLog, Data: outputs, Images: cmdImages, Files: cmdFiles}
+		return generate_output(start, result, codeclarity.FAILURE, append(cmdErrors, codeclarity_error))
 	}
 
-	// Find all files in the output path.
-	files, err = filepath.Glob(outputPath + "/*")
 	if err != nil {
-		log.Fatal(err)
+		classified := runner.ClassifyExit(ctx, err)
+		publicDescription := "The script failed to execute"
+		var oomErr *runner.OOMError
+		if errors.As(classified, &oomErr) {
+			publicDescription = "The script was killed for exceeding its memory limit"
+		}
+		codeclarity_error := exceptionManager.Error{
+			Private: exceptionManager.ErrorContent{
+				Description: fmt.Sprintf("%s\n%s", classified.Error(), finalLog),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: publicDescription,
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+		}
+		result := types.Result{Text: finalLog, Data: outputs, Images: cmdImages, Files: cmdFiles}
+		return generate_output(start, result, codeclarity.FAILURE, append(cmdErrors, codeclarity_error))
 	}
 
-	// Iterate over the files in the output path.
-	for _, f := range files {
-		// Skip the groups.json file.
-		if strings.Contains(f, "groups.json") {
-			continue
+	// Read the script's manifest.json, or synthesise one from the legacy
+	// single-PNG/TXT/JSON convention when it didn't write one, then merge in
+	// whatever the script separately declared via add-image/add-file so
+	// every artifact - regardless of which convention produced it - goes
+	// through the same content-addressing step exactly once.
+	manifest, err := loadManifest(outputPath)
+	if err != nil {
+		codeclarity_error := exceptionManager.Error{
+			Private: exceptionManager.ErrorContent{
+				Description: err.Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "The script's manifest.json is not valid JSON",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+		}
+		result := types.Result{Text: finalLog, Data: outputs, Images: cmdImages, Files: cmdFiles}
+		return generate_output(start, result, codeclarity.FAILURE, append(cmdErrors, codeclarity_error))
+	}
+	if manifest == nil {
+		manifest, err = synthesizeManifest(outputPath, declaredArtifactPaths(cmdImages, cmdFiles))
+		if err != nil {
+			log.Fatal(err)
 		}
+	}
+	manifest = mergeCommandArtifacts(manifest, cmdImages, cmdFiles)
 
-		// Check if the file is a JSON file.
-		if strings.HasSuffix(f, ".json") {
-			// Rename the JSON file to include the analysis ID.
-			newName := filepath.Join(dataPath, analysisId.String()+".json")
-			os.Rename(f, newName)
+	artifacts, err := materializeArtifacts(manifest, outputPath, dataPath)
+	if err != nil {
+		codeclarity_error := exceptionManager.Error{
+			Private: exceptionManager.ErrorContent{
+				Description: err.Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "Failed to collect the script's declared artifacts",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
 		}
+		result := types.Result{Text: finalLog, Data: outputs, Images: cmdImages, Files: cmdFiles}
+		return generate_output(start, result, codeclarity.FAILURE, append(cmdErrors, codeclarity_error))
+	}
+
+	// Fold each artifact into the result shape callers already understand:
+	// images and files add to their respective slices - keyed by the
+	// artifact's stable, content-addressed ID rather than its on-disk path,
+	// so clients never see the host's filesystem layout - JSON is schema
+	// validated and merged into outputs, and text is appended to the log.
+	var images []types.Image
+	var fileArtifacts []types.File
+	var rawJSON string
+	for _, artifact := range artifacts {
+		switch artifact.Kind {
+		case "image":
+			images = append(images, types.Image{Path: artifact.ID, Title: artifact.Title})
+		case "text":
+			content, err := os.ReadFile(artifact.Path)
+			if err != nil {
+				panic(fmt.Sprintf("Failed to read text artifact: %s", err.Error()))
+			}
+			processor.mu.Lock()
+			finalLog = finalLog + processor.mask(string(content))
+			processor.mu.Unlock()
+		case "json":
+			scriptData, raw, validationErrors := validateScriptJSON(pythonConfig, sourceCodeDir, artifact.Path)
+			rawJSON = raw
+			if len(validationErrors) > 0 {
+				setChatStatus(codeclarityDB, chat, "failed", finalLog)
+				result := types.Result{Text: finalLog, Data: outputs, Images: images, Files: fileArtifacts, Artifacts: artifacts, RawJSON: rawJSON}
+				return generate_output(start, result, codeclarity.FAILURE, append(cmdErrors, validationErrors...))
+			}
+			for k, v := range scriptData {
+				outputs[k] = v
+			}
+		default:
+			fileArtifacts = append(fileArtifacts, types.File{Path: artifact.ID})
+		}
+	}
+
+	setChatStatus(codeclarityDB, chat, "done", finalLog)
+
+	image := ""
+	if len(images) > 0 {
+		image = images[0].Path
+	}
+	result := types.Result{
+		Text:      finalLog,
+		Data:      outputs,
+		Images:    images,
+		Files:     fileArtifacts,
+		Artifacts: artifacts,
+		RawJSON:   rawJSON,
+		Image:     image,
 	}
 
 	// Generate the output with the image name, data, text content, status, and errors.
-	return generate_output(start, image, data, text, codeclarity.SUCCESS, []exceptionManager.Error{})
+	return generate_output(start, result, codeclarity.SUCCESS, cmdErrors)
+}
+
+// runningStatus formats the in-progress status text for chat.Messages[0].
+// pct is the latest value reported via `::progress pct=...::`; a script that
+// hasn't reported one yet (pct == 0) just gets the plain "running" status.
+func runningStatus(pct int) string {
+	if pct <= 0 {
+		return "running"
+	}
+	return fmt.Sprintf("running (%d%%)", pct)
+}
+
+// setChatStatus persists an in-progress status and log snapshot to
+// chat.Messages[0] so that a caller polling the chat row observes live
+// progress instead of only the final result.
+func setChatStatus(codeclarityDB *bun.DB, chat *types.Chat, status string, text string) {
+	if chat == nil || len(chat.Messages) == 0 {
+		return
+	}
+	chat.Messages[0].Status = status
+	chat.Messages[0].Text = text
+	_, err := codeclarityDB.NewUpdate().Model(chat).WherePK().Exec(context.Background())
+	if err != nil {
+		log.Printf("Failed to update chat status: %s", err.Error())
+	}
 }
 
 // generate_output generates the output object with the analysis results.
-func generate_output(start time.Time, imageName string, data any, text string, status codeclarity.AnalysisStatus, errors []exceptionManager.Error) types.Output {
+func generate_output(start time.Time, result types.Result, status codeclarity.AnalysisStatus, errors []exceptionManager.Error) types.Output {
 	// Calculate the analysis timing.
 	formattedStart, formattedEnd, delta := output_generator.GetAnalysisTiming(start)
 
 	// Create the output object.
 	output := types.Output{
-		Result: types.Result{
-			Image: imageName,
-			Data:  data,
-			Text:  text,
-		},
+		Result: result,
 		AnalysisInfo: types.AnalysisInfo{
 			Errors: errors,
 			Time: types.Time{
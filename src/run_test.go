@@ -0,0 +1,57 @@
+package python
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
+	"github.com/google/uuid"
+
+	"github.com/parithera/plugin-python/src/runner"
+	"github.com/parithera/plugin-python/src/types"
+)
+
+// fakeRunner is a runner.Runner that always runs a long sleep, so tests can
+// exercise ExecuteScript's cancellation/timeout path without depending on any
+// real sandboxing backend.
+type fakeRunner struct {
+	cleanupCalled bool
+}
+
+func (f *fakeRunner) Command(ctx context.Context, scriptPath string, outputPath string, limits runner.Limits) (*exec.Cmd, func(), error) {
+	cmd := exec.CommandContext(ctx, "sleep", "5")
+	return cmd, func() { f.cleanupCalled = true }, nil
+}
+
+func TestExecuteScriptAbortsOnContextTimeout(t *testing.T) {
+	sourceCodeDir := t.TempDir()
+	scriptDir := filepath.Join(sourceCodeDir, "python")
+	if err := os.MkdirAll(scriptDir, os.ModePerm); err != nil {
+		t.Fatalf("failed to create script dir: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(scriptDir, "script.py"), []byte("# unused by fakeRunner"), 0o644); err != nil {
+		t.Fatalf("failed to write script.py: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	fake := &fakeRunner{}
+	chat := &types.Chat{}
+
+	out := ExecuteScript(ctx, sourceCodeDir, uuid.New(), nil, chat, map[string]interface{}{}, fake)
+
+	if out.AnalysisInfo.Status != codeclarity.FAILURE {
+		t.Errorf("Status = %v, want FAILURE when the context times out", out.AnalysisInfo.Status)
+	}
+	if len(out.AnalysisInfo.Errors) == 0 {
+		t.Fatal("expected at least one error describing the abort")
+	}
+	if !fake.cleanupCalled {
+		t.Error("expected the runner's cleanup func to be called even when the script is aborted")
+	}
+}
@@ -0,0 +1,21 @@
+package runner
+
+// ChrootRunner executes the script inside a chroot built ahead of time at
+// ChrootDir (containing a python3 interpreter and its stdlib), with
+// SourceCodeDir bind-mounted in read-only - the same approach Skia's webtry
+// uses to run untrusted code via `--use_chroot`. Preparing ChrootDir itself
+// is an operational concern (see the project's deployment docs), not
+// something this runner does on the fly.
+type ChrootRunner struct {
+	// ChrootDir is the root of a prepared chroot containing /usr/bin/python3
+	// and its standard library.
+	ChrootDir string
+	// SourceCodeDir is the analysis's source tree, bind-mounted read-only at
+	// a fresh per-invocation directory (<ChrootDir>/src-*) before the script
+	// runs.
+	SourceCodeDir string
+}
+
+func NewChrootRunner(chrootDir string, sourceCodeDir string) *ChrootRunner {
+	return &ChrootRunner{ChrootDir: chrootDir, SourceCodeDir: sourceCodeDir}
+}
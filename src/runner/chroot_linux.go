@@ -0,0 +1,105 @@
+//go:build linux
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// Command bind-mounts SourceCodeDir read-only at a mountpoint unique to this
+// invocation - a fresh directory under ChrootDir, not the fixed
+// <ChrootDir>/src every earlier call used - so concurrent analyses sharing a
+// ChrootDir never race on, or read through, each other's source tree. Since
+// the script must still write its manifest.json and artifacts to outputPath,
+// a second, read-write bind mount of outputPath is layered on top of the
+// corresponding path inside the read-only tree, matching how webtry-style
+// chroots keep everything read-only except a scratch output directory.
+//
+// The returned cleanup unmounts both mounts (in reverse order) and removes
+// the mountpoint; callers must invoke it (e.g. via defer) once the command
+// has been waited on, on every path including errors, or the mounts leak.
+func (r *ChrootRunner) Command(ctx context.Context, scriptPath string, outputPath string, limits Limits) (*exec.Cmd, func(), error) {
+	relScript, err := filepath.Rel(r.SourceCodeDir, scriptPath)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("script path %q is not inside source dir %q: %w", scriptPath, r.SourceCodeDir, err)
+	}
+	relOutput, err := filepath.Rel(r.SourceCodeDir, outputPath)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("output path %q is not inside source dir %q: %w", outputPath, r.SourceCodeDir, err)
+	}
+
+	mountPoint, err := os.MkdirTemp(r.ChrootDir, "src-")
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to prepare chroot mountpoint: %w", err)
+	}
+
+	var mounted []string
+	cleanup := func() {
+		for i := len(mounted) - 1; i >= 0; i-- {
+			if err := syscall.Unmount(mounted[i], 0); err != nil {
+				fmt.Fprintf(os.Stderr, "chroot runner: failed to unmount %s: %v\n", mounted[i], err)
+				return
+			}
+		}
+		os.Remove(mountPoint)
+	}
+
+	if err := bindMountReadOnly(r.SourceCodeDir, mountPoint); err != nil {
+		os.Remove(mountPoint)
+		return nil, func() {}, fmt.Errorf("failed to bind-mount source into chroot: %w", err)
+	}
+	mounted = append(mounted, mountPoint)
+
+	outputMount := filepath.Join(mountPoint, relOutput)
+	if err := bindMountReadWrite(outputPath, outputMount); err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("failed to bind-mount writable output dir into chroot: %w", err)
+	}
+	mounted = append(mounted, outputMount)
+
+	mountName, err := filepath.Rel(r.ChrootDir, mountPoint)
+	if err != nil {
+		cleanup()
+		return nil, func() {}, fmt.Errorf("mountpoint %q is not inside chroot dir %q: %w", mountPoint, r.ChrootDir, err)
+	}
+
+	cmd := ulimitCommand(ctx, "/usr/bin/python3", []string{
+		filepath.Join("/", mountName, relScript),
+		filepath.Join("/", mountName, relOutput),
+	}, limits)
+	cmd.Dir = "/"
+	cmd.SysProcAttr = &syscall.SysProcAttr{Chroot: r.ChrootDir}
+	return cmd, cleanup, nil
+}
+
+// bindMountReadOnly bind-mounts src at dst and remounts the bind read-only,
+// mirroring `mount --bind` followed by `mount -o remount,ro,bind`.
+func bindMountReadOnly(src, dst string) error {
+	if err := syscall.Mount(src, dst, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("bind mount: %w", err)
+	}
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+	if err := syscall.Mount(src, dst, "", flags, ""); err != nil {
+		return fmt.Errorf("remount read-only: %w", err)
+	}
+	return nil
+}
+
+// bindMountReadWrite bind-mounts src at dst without remounting it read-only,
+// so a directory can be writable even though it sits inside an otherwise
+// read-only tree - each mountpoint carries its own flags independent of its
+// parent mount.
+func bindMountReadWrite(src, dst string) error {
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return fmt.Errorf("prepare writable mountpoint: %w", err)
+	}
+	if err := syscall.Mount(src, dst, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("bind mount: %w", err)
+	}
+	return nil
+}
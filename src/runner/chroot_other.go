@@ -0,0 +1,13 @@
+//go:build !linux
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+func (r *ChrootRunner) Command(ctx context.Context, scriptPath string, outputPath string, limits Limits) (*exec.Cmd, func(), error) {
+	return nil, func() {}, fmt.Errorf("python.backend \"chroot\" requires Linux")
+}
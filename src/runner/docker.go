@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// DockerRunner executes the script inside a container built from Image,
+// with SourceCodeDir mounted read-only and outputPath separately bind-mounted
+// read-write (so the script can still write manifest.json and its artifacts)
+// so it can't touch anything else on the host filesystem.
+type DockerRunner struct {
+	Image         string
+	SourceCodeDir string
+	// CPUs limits the number of CPUs made available to the container (e.g.
+	// "1.0"). Empty means Docker's default (unlimited).
+	CPUs string
+}
+
+func NewDockerRunner(image string, sourceCodeDir string) *DockerRunner {
+	return &DockerRunner{Image: image, SourceCodeDir: sourceCodeDir}
+}
+
+func (r *DockerRunner) Command(ctx context.Context, scriptPath string, outputPath string, limits Limits) (*exec.Cmd, func(), error) {
+	relScript, err := filepath.Rel(r.SourceCodeDir, scriptPath)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	relOutput, err := filepath.Rel(r.SourceCodeDir, outputPath)
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	args := []string{
+		"run", "--rm",
+		"--network", "none",
+		"--tmpfs", "/work",
+		"-v", r.SourceCodeDir + ":/src:ro",
+		// outputPath is layered read-write on top of the read-only source
+		// mount, at the same path the script sees it at, so it can still
+		// write manifest.json and its declared artifacts there.
+		"-v", outputPath + ":" + filepath.Join("/src", relOutput),
+		"-w", "/work",
+	}
+	if limits.MemoryBytes > 0 {
+		args = append(args, "--memory", strconv.FormatInt(limits.MemoryBytes, 10))
+	}
+	if limits.CPUSeconds > 0 {
+		args = append(args, "--ulimit", "cpu="+strconv.Itoa(limits.CPUSeconds))
+	}
+	if limits.Processes > 0 {
+		args = append(args, "--pids-limit", strconv.Itoa(limits.Processes))
+	}
+	if r.CPUs != "" {
+		args = append(args, "--cpus", r.CPUs)
+	}
+	args = append(args, r.Image, "python3", filepath.Join("/src", relScript), filepath.Join("/src", relOutput))
+
+	return exec.CommandContext(ctx, "docker", args...), func() {}, nil
+}
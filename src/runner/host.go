@@ -0,0 +1,19 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+)
+
+// HostRunner executes the script directly with whatever "python3" is on
+// PATH. This is the original, unsandboxed behaviour, kept as the default so
+// existing deployments don't change until they opt into a stronger backend.
+type HostRunner struct{}
+
+func NewHostRunner() *HostRunner {
+	return &HostRunner{}
+}
+
+func (r *HostRunner) Command(ctx context.Context, scriptPath string, outputPath string, limits Limits) (*exec.Cmd, func(), error) {
+	return ulimitCommand(ctx, "python3", []string{scriptPath, outputPath}, limits), func() {}, nil
+}
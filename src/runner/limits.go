@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ulimitCommand wraps interpreter/args in a shell that applies limits via
+// ulimit before exec-ing into the real process, so the host, venv and chroot
+// backends enforce the same memory/CPU/process caps without each needing its
+// own cgroup/rlimit plumbing. DockerRunner applies the same caps through
+// `docker run` flags instead.
+func ulimitCommand(ctx context.Context, interpreter string, args []string, limits Limits) *exec.Cmd {
+	var prefix strings.Builder
+	if limits.MemoryBytes > 0 {
+		fmt.Fprintf(&prefix, "ulimit -v %d; ", limits.MemoryBytes/1024)
+	}
+	if limits.CPUSeconds > 0 {
+		fmt.Fprintf(&prefix, "ulimit -t %d; ", limits.CPUSeconds)
+	}
+	if limits.Processes > 0 {
+		// bash and busybox take -u for the process cap; dash only knows -p.
+		fmt.Fprintf(&prefix, "{ ulimit -u %d || ulimit -p %d; } 2>/dev/null; ", limits.Processes, limits.Processes)
+	}
+
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(interpreter))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	script := prefix.String() + "exec " + strings.Join(parts, " ")
+
+	return exec.CommandContext(ctx, "sh", "-c", script)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
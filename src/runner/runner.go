@@ -0,0 +1,88 @@
+// Package runner selects how and where the untrusted Python analysis script
+// actually executes: directly on the host, inside a per-analysis virtualenv,
+// chrooted, or inside a container. ExecuteScript depends only on the Runner
+// interface, so callers (and tests) can swap in a fake without touching the
+// execution/streaming logic.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// Limits bounds the resources a script is allowed to consume. A zero value
+// for any field means "no limit".
+type Limits struct {
+	// MemoryBytes is the maximum amount of (virtual) memory the script may
+	// allocate.
+	MemoryBytes int64
+	// CPUSeconds is the maximum amount of CPU time the script may consume.
+	CPUSeconds int
+	// Processes is the maximum number of processes/threads the script (and
+	// any children it spawns) may create.
+	Processes int
+}
+
+// Runner builds the command that will execute the script at scriptPath,
+// passing outputPath as its sole argument, the way ExecuteScript already
+// invokes script.py. The returned command is not started; ExecuteScript
+// wires up its context cancellation, stdout/stderr pipes and WaitDelay
+// uniformly regardless of backend.
+//
+// The returned cleanup func releases anything Command set up for this one
+// invocation (e.g. a chroot bind mount) and must be called by the caller -
+// typically via defer - once the command has been waited on, whether it
+// succeeded, failed, or was never started at all.
+type Runner interface {
+	Command(ctx context.Context, scriptPath string, outputPath string, limits Limits) (cmd *exec.Cmd, cleanup func(), err error)
+}
+
+// OOMError indicates a script was killed for exceeding its memory limit.
+type OOMError struct {
+	Cause error
+}
+
+func (e *OOMError) Error() string {
+	return fmt.Sprintf("script exceeded its memory limit: %v", e.Cause)
+}
+
+func (e *OOMError) Unwrap() error { return e.Cause }
+
+// TimeoutError indicates a script was killed for exceeding its time budget.
+type TimeoutError struct {
+	Cause error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("script exceeded its time budget: %v", e.Cause)
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Cause }
+
+// ClassifyExit turns the raw error from cmd.Wait() into a typed OOMError or
+// TimeoutError when the exit reflects a resource limit kicking in, so
+// callers can distinguish "the script failed" from "we killed the script".
+func ClassifyExit(ctx context.Context, waitErr error) error {
+	if waitErr == nil {
+		return nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return &TimeoutError{Cause: waitErr}
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			// 137 is the conventional "killed by SIGKILL" exit code used by
+			// Docker's OOM killer; a bare SIGKILL is what ulimit -v and
+			// cgroup memory limits converge on for the other backends.
+			if status.ExitStatus() == 137 || (status.Signaled() && status.Signal() == syscall.SIGKILL) {
+				return &OOMError{Cause: waitErr}
+			}
+		}
+	}
+	return waitErr
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestClassifyExitNilError(t *testing.T) {
+	if err := ClassifyExit(context.Background(), nil); err != nil {
+		t.Errorf("ClassifyExit(nil) = %v, want nil", err)
+	}
+}
+
+func TestClassifyExitDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	time.Sleep(time.Millisecond) // make sure the deadline has actually passed
+
+	waitErr := errors.New("boom")
+	classified := ClassifyExit(ctx, waitErr)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(classified, &timeoutErr) {
+		t.Fatalf("ClassifyExit() = %v (%T), want a *TimeoutError", classified, classified)
+	}
+	if timeoutErr.Cause != waitErr {
+		t.Errorf("TimeoutError.Cause = %v, want %v", timeoutErr.Cause, waitErr)
+	}
+}
+
+func TestClassifyExitOOMExitCode(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "exit 137")
+	waitErr := cmd.Run()
+	if waitErr == nil {
+		t.Fatal("expected the command to exit non-zero")
+	}
+
+	classified := ClassifyExit(context.Background(), waitErr)
+
+	var oomErr *OOMError
+	if !errors.As(classified, &oomErr) {
+		t.Fatalf("ClassifyExit() = %v (%T), want a *OOMError for exit code 137", classified, classified)
+	}
+}
+
+func TestClassifyExitOrdinaryFailurePassesThrough(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "exit 1")
+	waitErr := cmd.Run()
+	if waitErr == nil {
+		t.Fatal("expected the command to exit non-zero")
+	}
+
+	classified := ClassifyExit(context.Background(), waitErr)
+	if classified != waitErr {
+		t.Errorf("ClassifyExit() = %v, want the original error unchanged for an ordinary non-zero exit", classified)
+	}
+}
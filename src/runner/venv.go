@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// VenvRunner executes the script inside a per-requirements virtualenv, built
+// from a requirements.txt next to script.py. Venvs are cached under baseDir,
+// keyed by the hash of their requirements.txt, so unrelated analyses that
+// declare the same dependencies reuse one environment instead of rebuilding
+// it on every run.
+type VenvRunner struct {
+	baseDir string
+}
+
+func NewVenvRunner(baseDir string) *VenvRunner {
+	return &VenvRunner{baseDir: baseDir}
+}
+
+func (r *VenvRunner) Command(ctx context.Context, scriptPath string, outputPath string, limits Limits) (*exec.Cmd, func(), error) {
+	requirementsPath := filepath.Join(filepath.Dir(scriptPath), "requirements.txt")
+
+	venvDir, err := r.ensureVenv(ctx, requirementsPath)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to prepare venv: %w", err)
+	}
+
+	interpreter := filepath.Join(venvDir, "bin", "python3")
+	return ulimitCommand(ctx, interpreter, []string{scriptPath, outputPath}, limits), func() {}, nil
+}
+
+// ensureVenv returns the directory of a virtualenv built from
+// requirementsPath, creating and `pip install`-ing it on first use and
+// reusing it (keyed by content hash) on every subsequent call. Building is
+// guarded by an flock on a lockfile next to venvDir rather than an in-memory
+// mutex: selectRunner constructs a fresh VenvRunner per analysis run, so only
+// a lock held at the OS level serializes two concurrent processes (or
+// goroutines across separate VenvRunners) that land on the same requirements
+// hash - otherwise they'd both see the cache dir missing and race
+// `python3 -m venv` + `pip install` into the same directory.
+func (r *VenvRunner) ensureVenv(ctx context.Context, requirementsPath string) (string, error) {
+	hash := "no-requirements"
+	requirements, err := os.ReadFile(requirementsPath)
+	hasRequirements := err == nil
+	if hasRequirements {
+		sum := sha256.Sum256(requirements)
+		hash = hex.EncodeToString(sum[:])
+	}
+	venvDir := filepath.Join(r.baseDir, hash)
+	interpreterPath := filepath.Join(venvDir, "bin", "python3")
+
+	if _, err := os.Stat(interpreterPath); err == nil {
+		return venvDir, nil // already cached from a previous run
+	}
+
+	if err := os.MkdirAll(r.baseDir, 0o755); err != nil {
+		return "", err
+	}
+
+	lock, err := os.OpenFile(venvDir+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open venv lockfile: %w", err)
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return "", fmt.Errorf("failed to lock venv build: %w", err)
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+
+	// Another process may have finished building venvDir while we were
+	// waiting for the lock; re-check before doing the work again.
+	if _, err := os.Stat(interpreterPath); err == nil {
+		return venvDir, nil
+	}
+
+	if err := exec.CommandContext(ctx, "python3", "-m", "venv", venvDir).Run(); err != nil {
+		return "", fmt.Errorf("python3 -m venv: %w", err)
+	}
+	if hasRequirements {
+		pip := filepath.Join(venvDir, "bin", "pip")
+		if err := exec.CommandContext(ctx, pip, "install", "-r", requirementsPath).Run(); err != nil {
+			return "", fmt.Errorf("pip install -r %s: %w", requirementsPath, err)
+		}
+	}
+	return venvDir, nil
+}
@@ -0,0 +1,169 @@
+package python
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	exceptionManager "github.com/CodeClarityCE/utility-types/exceptions"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schemaCache holds compiled schemas keyed by the sha256 hash of their raw
+// bytes, so repeated runs against the same schema.json don't re-parse it.
+var schemaCache = struct {
+	mu       sync.Mutex
+	compiled map[string]*gojsonschema.Schema
+}{compiled: map[string]*gojsonschema.Schema{}}
+
+// resolveSchemaPath finds the JSON Schema that a script's JSON output must
+// conform to: an explicit `python.schema` path in the analysis config takes
+// precedence, otherwise a schema.json next to script.py is used if present.
+// An empty result means no schema is declared, and validation is skipped.
+func resolveSchemaPath(pythonConfig map[string]interface{}, sourceCodeDir string) string {
+	if configured, ok := pythonConfig["schema"].(string); ok && configured != "" {
+		if path.IsAbs(configured) {
+			return configured
+		}
+		return path.Join(sourceCodeDir, configured)
+	}
+
+	defaultPath := path.Join(sourceCodeDir, "python", "schema.json")
+	if _, err := os.Stat(defaultPath); err == nil {
+		return defaultPath
+	}
+	return ""
+}
+
+// compileSchema loads and compiles the schema at schemaPath, reusing a
+// cached copy when its contents haven't changed.
+func compileSchema(schemaPath string) (*gojsonschema.Schema, error) {
+	raw, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(raw)
+	key := hex.EncodeToString(hash[:])
+
+	schemaCache.mu.Lock()
+	defer schemaCache.mu.Unlock()
+	if schema, ok := schemaCache.compiled[key]; ok {
+		return schema, nil
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return nil, err
+	}
+	schemaCache.compiled[key] = schema
+	return schema, nil
+}
+
+// validateAgainstSchema validates raw against schema and, on failure,
+// returns one exceptionManager.Error per violation whose private
+// description carries the violation's JSON-Pointer path and message.
+func validateAgainstSchema(schema *gojsonschema.Schema, raw []byte) []exceptionManager.Error {
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return []exceptionManager.Error{{
+			Private: exceptionManager.ErrorContent{
+				Description: err.Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "The script's JSON output could not be validated",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+		}}
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	errs := make([]exceptionManager.Error, 0, len(result.Errors()))
+	for _, violation := range result.Errors() {
+		errs = append(errs, exceptionManager.Error{
+			Private: exceptionManager.ErrorContent{
+				Description: fmt.Sprintf("%s: %s", fieldToJSONPointer(violation.Field()), violation.Description()),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "The script's JSON output did not match its declared schema",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+		})
+	}
+	return errs
+}
+
+// fieldToJSONPointer turns a gojsonschema dotted field path, e.g.
+// "(root).items.0.name", into a JSON Pointer, e.g. "/items/0/name".
+func fieldToJSONPointer(field string) string {
+	field = strings.TrimPrefix(field, "(root)")
+	field = strings.TrimPrefix(field, ".")
+	if field == "" {
+		return "/"
+	}
+	return "/" + strings.ReplaceAll(field, ".", "/")
+}
+
+// validateScriptJSON reads the script's JSON output file and, if a schema is
+// declared for this analysis, validates it before the caller is allowed to
+// merge it into the chat's persisted data. It always returns the raw JSON
+// text so callers can stash it under Message.RawJSON for debugging, even
+// when validation fails.
+func validateScriptJSON(pythonConfig map[string]interface{}, sourceCodeDir string, jsonPath string) (data map[string]interface{}, rawJSON string, errs []exceptionManager.Error) {
+	raw, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, "", []exceptionManager.Error{{
+			Private: exceptionManager.ErrorContent{
+				Description: err.Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "Failed to read the script's JSON output",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+		}}
+	}
+	rawJSON = string(raw)
+
+	if schemaPath := resolveSchemaPath(pythonConfig, sourceCodeDir); schemaPath != "" {
+		schema, err := compileSchema(schemaPath)
+		if err != nil {
+			return nil, rawJSON, []exceptionManager.Error{{
+				Private: exceptionManager.ErrorContent{
+					Description: err.Error(),
+					Type:        exceptionManager.GENERIC_ERROR,
+				},
+				Public: exceptionManager.ErrorContent{
+					Description: "Failed to compile the declared JSON schema",
+					Type:        exceptionManager.GENERIC_ERROR,
+				},
+			}}
+		}
+		if violations := validateAgainstSchema(schema, raw); len(violations) > 0 {
+			return nil, rawJSON, violations
+		}
+	}
+
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, rawJSON, []exceptionManager.Error{{
+			Private: exceptionManager.ErrorContent{
+				Description: err.Error(),
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+			Public: exceptionManager.ErrorContent{
+				Description: "The script's JSON output is not valid JSON",
+				Type:        exceptionManager.GENERIC_ERROR,
+			},
+		}}
+	}
+	return data, rawJSON, nil
+}
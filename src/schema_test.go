@@ -0,0 +1,89 @@
+package python
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestFieldToJSONPointer(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"(root)", "/"},
+		{"(root).name", "/name"},
+		{"(root).items.0.name", "/items/0/name"},
+	}
+
+	for _, tt := range tests {
+		got := fieldToJSONPointer(tt.field)
+		if got != tt.want {
+			t.Errorf("fieldToJSONPointer(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	raw := []byte(`{
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"required": ["name"]
+	}`)
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		t.Fatalf("failed to compile schema: %s", err)
+	}
+
+	t.Run("valid document produces no errors", func(t *testing.T) {
+		errs := validateAgainstSchema(schema, []byte(`{"name": "ok"}`))
+		if len(errs) != 0 {
+			t.Errorf("got %d errors for a valid document, want 0", len(errs))
+		}
+	})
+
+	t.Run("violation is reported with a JSON pointer", func(t *testing.T) {
+		errs := validateAgainstSchema(schema, []byte(`{}`))
+		if len(errs) != 1 {
+			t.Fatalf("got %d errors for a missing required field, want 1", len(errs))
+		}
+		if errs[0].Private.Description == "" {
+			t.Errorf("expected a non-empty private description")
+		}
+	})
+}
+
+func TestResolveSchemaPath(t *testing.T) {
+	sourceCodeDir := t.TempDir()
+
+	t.Run("no schema configured or present", func(t *testing.T) {
+		if got := resolveSchemaPath(map[string]interface{}{}, sourceCodeDir); got != "" {
+			t.Errorf("resolveSchemaPath() = %q, want empty", got)
+		}
+	})
+
+	t.Run("explicit config path wins", func(t *testing.T) {
+		got := resolveSchemaPath(map[string]interface{}{"schema": "custom/schema.json"}, sourceCodeDir)
+		want := filepath.Join(sourceCodeDir, "custom/schema.json")
+		if got != want {
+			t.Errorf("resolveSchemaPath() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("falls back to python/schema.json when present", func(t *testing.T) {
+		pythonDir := filepath.Join(sourceCodeDir, "python")
+		if err := os.MkdirAll(pythonDir, 0o755); err != nil {
+			t.Fatalf("failed to create python dir: %s", err)
+		}
+		schemaPath := filepath.Join(pythonDir, "schema.json")
+		if err := os.WriteFile(schemaPath, []byte(`{}`), 0o644); err != nil {
+			t.Fatalf("failed to write schema.json: %s", err)
+		}
+
+		if got := resolveSchemaPath(map[string]interface{}{}, sourceCodeDir); got != schemaPath {
+			t.Errorf("resolveSchemaPath() = %q, want %q", got, schemaPath)
+		}
+	})
+}
@@ -13,14 +13,18 @@ type Chat struct {
 }
 
 type Message struct {
-	Request   string   `json:"request"`
-	Code      string   `json:"code"`
-	Followup  []string `json:"followup"`
-	Text      string   `json:"text"`
-	JSON      any      `json:"json"`
-	Image     string   `json:"image"`
-	Agent     string   `json:"agent"`
-	Error     string   `json:"error"`
-	Status    string   `json:"status"`
-	Timestamp string   `json:"timestamp"`
+	Request   string     `json:"request"`
+	Code      string     `json:"code"`
+	Followup  []string   `json:"followup"`
+	Text      string     `json:"text"`
+	JSON      any        `json:"json"`
+	RawJSON   string     `json:"rawJson"`
+	Image     string     `json:"image"`
+	Images    []Image    `json:"images"`
+	Files     []File     `json:"files"`
+	Artifacts []Artifact `json:"artifacts"`
+	Agent     string     `json:"agent"`
+	Error     string     `json:"error"`
+	Status    string     `json:"status"`
+	Timestamp string     `json:"timestamp"`
 }
@@ -0,0 +1,76 @@
+package types
+
+import (
+	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
+	exceptionManager "github.com/CodeClarityCE/utility-types/exceptions"
+)
+
+// Image is a single plot or picture produced by a script, surfaced via the
+// `::add-image path=...,title=...::` workflow command.
+type Image struct {
+	Path  string `json:"path"`
+	Title string `json:"title"`
+}
+
+// File is a single non-image artifact produced by a script, surfaced via the
+// `::add-file path=...::` workflow command.
+type File struct {
+	Path string `json:"path"`
+}
+
+// Artifact is a single file a script produced, as declared in its
+// manifest.json (see manifest.go). Path is content-addressed - a sha256
+// prefix of the file's bytes plus its original extension - so the same
+// artifact produced across reruns or analyses resolves to the same ID
+// instead of being duplicated on disk.
+type Artifact struct {
+	ID          string `json:"id"`
+	Path        string `json:"path"`
+	Kind        string `json:"kind"`
+	MIME        string `json:"mime"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Role        string `json:"role"`
+}
+
+// Result carries everything a script produced during a run: its merged
+// `set-output` values, every image and file it emitted, and the (masked)
+// log text.
+type Result struct {
+	Text      string         `json:"text"`
+	Data      map[string]any `json:"data"`
+	Images    []Image        `json:"images"`
+	Files     []File         `json:"files"`
+	Artifacts []Artifact     `json:"artifacts"`
+
+	// RawJSON is the script's JSON output exactly as it wrote it, kept
+	// around for debugging even when it failed schema validation (in which
+	// case Data holds only the script's set-output values, not that JSON).
+	RawJSON string `json:"rawJson"`
+
+	// Image is the legacy single-image reference kept for callers that
+	// have not migrated to Images yet. On a successful run it is set to the
+	// first image, if any; failed or aborted runs leave it empty.
+	Image string `json:"image"`
+}
+
+// Time records the wall-clock boundaries of an analysis run.
+type Time struct {
+	AnalysisStartTime string  `json:"analysis_start_time"`
+	AnalysisEndTime   string  `json:"analysis_end_time"`
+	AnalysisDeltaTime float64 `json:"analysis_delta_time"`
+}
+
+// AnalysisInfo summarises how a run went: timing, final status, and any
+// errors or warnings raised along the way.
+type AnalysisInfo struct {
+	Errors []exceptionManager.Error   `json:"errors"`
+	Time   Time                       `json:"time"`
+	Status codeclarity.AnalysisStatus `json:"status"`
+}
+
+// Output is the top-level value returned by ExecuteScript and Start.
+type Output struct {
+	Result       Result       `json:"result"`
+	AnalysisInfo AnalysisInfo `json:"analysis_info"`
+}